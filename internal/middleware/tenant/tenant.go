@@ -0,0 +1,36 @@
+// Package tenant threads a tenant identifier through a request's context,
+// the same way internal/middleware/datastore threads a Datastore: something
+// upstream (a gRPC interceptor, typically) calls ContextWithTenantID once
+// per request, and anything downstream that needs to know the current
+// tenant - such as a namespace.Manager configured with
+// namespace.WithTenantScoping(Resolver) - reads it back out with
+// FromContext.
+package tenant
+
+import "context"
+
+type contextKeyType struct{}
+
+var contextKey contextKeyType
+
+// ContextWithTenantID returns a copy of ctx carrying tenantID, for a later
+// FromContext lookup.
+func ContextWithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, contextKey, tenantID)
+}
+
+// FromContext returns the tenant ID previously stored in ctx via
+// ContextWithTenantID, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(contextKey).(string)
+	return tenantID, ok
+}
+
+// Resolver is a ready-made namespace.WithTenantScoping resolver for
+// deployments that plumb the tenant ID via ContextWithTenantID. It resolves
+// to "" - the shared default tenant - for any request that never had one
+// set, so enabling it is safe even before every caller has been migrated.
+func Resolver(ctx context.Context) string {
+	tenantID, _ := FromContext(ctx)
+	return tenantID
+}
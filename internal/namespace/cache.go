@@ -0,0 +1,83 @@
+package namespace
+
+import (
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+)
+
+// NamespaceCache is the storage interface used by cachingManager to hold
+// looked-up namespace definitions. Implementations may be purely local to
+// the process (e.g. an in-memory ristretto cache) or shared across a fleet
+// of SpiceDB instances (e.g. Redis).
+type NamespaceCache interface {
+	// Get returns the value stored for key, if any.
+	Get(key string) (value interface{}, found bool)
+
+	// Set stores value under key with the given cost and TTL. A ttl of zero
+	// means the entry should not expire on its own. Backends that don't
+	// support a notion of cost or TTL may ignore those arguments.
+	Set(key string, value interface{}, cost int64, ttl time.Duration)
+
+	// Delete removes the entry stored for key, if any. It is not an error to
+	// delete a key that is not present.
+	Delete(key string)
+
+	// Clear drops every entry currently held by the cache, for use when a
+	// bulk invalidation (e.g. a schema migration) makes tracking individual
+	// keys impractical.
+	Clear() error
+
+	// Close releases any resources held by the cache. It must be safe to call
+	// exactly once, after which the cache must no longer be used.
+	Close() error
+}
+
+// ristrettoCache adapts a *ristretto.Cache to the NamespaceCache interface.
+type ristrettoCache struct {
+	c *ristretto.Cache
+}
+
+// newRistrettoCache creates an in-process NamespaceCache backed by ristretto.
+func newRistrettoCache(cacheConfig *ristretto.Config) (NamespaceCache, error) {
+	if cacheConfig == nil {
+		cacheConfig = &ristretto.Config{
+			NumCounters: 1e4,     // number of keys to track frequency of (10k).
+			MaxCost:     1 << 24, // maximum cost of cache (16MB).
+			BufferItems: 64,      // number of keys per Get buffer.
+		}
+	}
+
+	cache, err := ristretto.NewCache(cacheConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ristrettoCache{c: cache}, nil
+}
+
+func (rc *ristrettoCache) Get(key string) (interface{}, bool) {
+	return rc.c.Get(key)
+}
+
+func (rc *ristrettoCache) Set(key string, value interface{}, cost int64, ttl time.Duration) {
+	if ttl <= 0 {
+		rc.c.Set(key, value, cost)
+		return
+	}
+	rc.c.SetWithTTL(key, value, cost, ttl)
+}
+
+func (rc *ristrettoCache) Delete(key string) {
+	rc.c.Del(key)
+}
+
+func (rc *ristrettoCache) Clear() error {
+	rc.c.Clear()
+	return nil
+}
+
+func (rc *ristrettoCache) Close() error {
+	rc.c.Close()
+	return nil
+}
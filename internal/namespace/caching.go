@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	v0 "github.com/authzed/authzed-go/proto/authzed/api/v0"
@@ -23,42 +25,256 @@ const (
 
 type cachingManager struct {
 	expiration  time.Duration
-	c           *ristretto.Cache
+	c           NamespaceCache
 	readNsGroup singleflight.Group
+
+	tsCache     NamespaceCache
+	readTsGroup singleflight.Group
+
+	invalidation      InvalidationTransport
+	invalidationUnsub func()
+
+	tenantResolver func(ctx context.Context) string
+	shardsMu       sync.Mutex
+	shards         map[string]*tenantShard
+}
+
+// tenantShard holds the cache bookkeeping - which keys belong to which
+// namespace, and which type-system entries depend on which namespace - for
+// a single tenant. Splitting this out per tenant, rather than keeping one
+// global set of maps, is what lets EvictTenant drop a tenant's entries
+// without scanning or touching any other tenant's.
+type tenantShard struct {
+	mu     sync.Mutex
+	nsKeys map[string][]string
+
+	// tsDeps is keyed by namespace name alone, not by (namespace, revision)
+	// pair: since invalidation is itself namespace-wide regardless of
+	// revision (see InvalidateNamespace), tracking which revision a
+	// dependency was read at wouldn't change what gets evicted. A future
+	// per-revision invalidation feature would need this rebuilt to key on
+	// (nsName, revision) instead.
+	tsDepsMu sync.Mutex
+	tsDeps   map[string][]string
+
+	// evicted is set once EvictTenant has removed this shard from
+	// cachingManager.shards. trackKey and trackTypeSystemDependency consult
+	// it (under the same lock that guards the map they'd otherwise write
+	// to) so a write racing an in-flight EvictTenant can't resurrect an
+	// entry into a shard nothing will ever evict again.
+	evicted uint32
+}
+
+func (s *tenantShard) markEvicted() {
+	atomic.StoreUint32(&s.evicted, 1)
+}
+
+func (s *tenantShard) isEvicted() bool {
+	return atomic.LoadUint32(&s.evicted) == 1
+}
+
+func newTenantShard() *tenantShard {
+	return &tenantShard{
+		nsKeys: make(map[string][]string),
+		tsDeps: make(map[string][]string),
+	}
+}
+
+// TenantAwareManager is implemented by a Manager constructed with
+// WithTenantScoping. It exposes operations that apply to a single tenant's
+// slice of the cache, without requiring every other tenant's entries to be
+// scanned or evicted alongside it.
+type TenantAwareManager interface {
+	Manager
+
+	// EvictTenant evicts every entry cached for tenantID - across every
+	// namespace, revision, and cached type system - and drops its shard
+	// bookkeeping. Use this when a tenant is deleted, or when rolling out a
+	// schema change tenant-by-tenant and the previous schema's entries must
+	// not linger.
+	EvictTenant(tenantID string) error
+
+	// TenantMetrics reports the size of tenantID's cache footprint, for
+	// operators deciding whether an EvictTenant is warranted.
+	TenantMetrics(tenantID string) TenantCacheMetrics
+
+	// InvalidateNamespaceForTenant is the tenant-scoped counterpart to
+	// InvalidatingManager's InvalidateNamespace; see its doc comment on
+	// cachingManager.
+	InvalidateNamespaceForTenant(tenantID, nsName string, revision decimal.Decimal) error
+}
+
+// TenantCacheMetrics reports how much of a cachingManager's bookkeeping is
+// attributable to a single tenant.
+type TenantCacheMetrics struct {
+	// CachedNamespaces is the number of distinct namespaces with at least
+	// one cached entry for this tenant.
+	CachedNamespaces int
+
+	// CachedTypeSystems is the number of distinct namespaces with at least
+	// one cached type-system entry depending on them, for this tenant.
+	CachedTypeSystems int
+}
+
+// Option configures a cachingManager returned by NewCachingNamespaceManager.
+type Option func(*cachingManager) error
+
+// WithCacheBackend selects the NamespaceCache implementation used to store
+// looked-up namespace definitions. Defaults to an in-process ristretto
+// cache configured from the cacheConfig passed to
+// NewCachingNamespaceManager; pass NewRedisCache(...) here to share a
+// namespace cache across a fleet of SpiceDB instances instead.
+func WithCacheBackend(cache NamespaceCache) Option {
+	return func(nsc *cachingManager) error {
+		nsc.c = cache
+		return nil
+	}
+}
+
+// WithInvalidationTransport causes the manager to publish an
+// InvalidationEvent whenever InvalidateNamespace is called, and to evict
+// matching entries from its local cache whenever one is received -
+// including from itself. Pass NewRedisInvalidationTransport(...) to keep a
+// fleet of SpiceDB instances in sync, or NewInProcessInvalidationTransport()
+// to keep multiple managers within one process in sync.
+func WithInvalidationTransport(transport InvalidationTransport) Option {
+	return func(nsc *cachingManager) error {
+		nsc.invalidation = transport
+		return nil
+	}
+}
+
+// WithTypeSystemCacheBackend selects the NamespaceCache implementation used
+// to store built *NamespaceTypeSystem values, keyed separately from (but
+// alongside) namespace definitions. Defaults to its own in-process
+// ristretto cache configured from the cacheConfig passed to
+// NewCachingNamespaceManager. cache returned by NewRedisCache is rejected:
+// that backend only knows how to marshal *v0.NamespaceDefinition values, so
+// it would silently miss on every *NamespaceTypeSystem lookup instead of
+// actually sharing type systems across instances.
+func WithTypeSystemCacheBackend(cache NamespaceCache) Option {
+	return func(nsc *cachingManager) error {
+		if _, ok := cache.(*redisCache); ok {
+			return errors.New("redisCache stores only *v0.NamespaceDefinition values and cannot back the type-system cache; pass it to WithCacheBackend instead")
+		}
+		nsc.tsCache = cache
+		return nil
+	}
+}
+
+// WithTenantScoping partitions the manager's cache bookkeeping by tenant, so
+// that a single SpiceDB process serving multiple tenants over a shared
+// cache backend cannot leak one tenant's cached namespace definitions or
+// type systems to another. resolver is called on every cache access to
+// determine the current tenant ID from ctx - pass tenant.Resolver to use
+// the tenant package's context plumbing, or supply your own to derive it
+// from a request header, a SpiceDB caveat, an mTLS SAN, or however else
+// tenants are identified in your deployment. Without this option, every
+// caller shares a single implicit tenant, matching prior behavior.
+func WithTenantScoping(resolver func(ctx context.Context) string) Option {
+	return func(nsc *cachingManager) error {
+		nsc.tenantResolver = resolver
+		return nil
+	}
 }
 
 func NewCachingNamespaceManager(
 	expiration time.Duration,
 	cacheConfig *ristretto.Config,
+	options ...Option,
 ) (Manager, error) {
-	if cacheConfig == nil {
-		cacheConfig = &ristretto.Config{
-			NumCounters: 1e4,     // number of keys to track frequency of (10k).
-			MaxCost:     1 << 24, // maximum cost of cache (16MB).
-			BufferItems: 64,      // number of keys per Get buffer.
+	nsc := &cachingManager{
+		expiration: expiration,
+		shards:     make(map[string]*tenantShard),
+	}
+
+	for _, opt := range options {
+		if err := opt(nsc); err != nil {
+			return nil, fmt.Errorf(errInitialization, err)
 		}
 	}
 
-	cache, err := ristretto.NewCache(cacheConfig)
-	if err != nil {
-		return nil, fmt.Errorf(errInitialization, err)
+	if nsc.c == nil {
+		cache, err := newRistrettoCache(cacheConfig)
+		if err != nil {
+			return nil, fmt.Errorf(errInitialization, err)
+		}
+		nsc.c = cache
 	}
 
-	return &cachingManager{
-		expiration: expiration,
-		c:          cache,
-	}, nil
+	if nsc.tsCache == nil {
+		cache, err := newRistrettoCache(cacheConfig)
+		if err != nil {
+			return nil, fmt.Errorf(errInitialization, err)
+		}
+		nsc.tsCache = cache
+	}
+
+	if nsc.invalidation != nil {
+		unsub, err := nsc.invalidation.Subscribe(nsc.handleInvalidation)
+		if err != nil {
+			return nil, fmt.Errorf(errInitialization, err)
+		}
+		nsc.invalidationUnsub = unsub
+	}
+
+	return nsc, nil
 }
 
 func (nsc *cachingManager) ReadNamespaceAndTypes(ctx context.Context, nsName string, revision decimal.Decimal) (*v0.NamespaceDefinition, *NamespaceTypeSystem, error) {
-	nsDef, err := nsc.ReadNamespace(ctx, nsName, revision)
+	ctx, span := tracer.Start(ctx, "ReadNamespaceAndTypes")
+	defer span.End()
+
+	ds := datastoremw.MustFromContext(ctx)
+	tenantID := nsc.tenantID(ctx)
+
+	nsRevisionKey, err := ds.NamespaceCacheKey(nsName, revision)
 	if err != nil {
-		return nsDef, nil, err
+		return nil, nil, err
 	}
+	tsKey := scopeKeyToTenant(tenantID, typeSystemCacheKey(nsRevisionKey))
+	shard := nsc.shardFor(tenantID)
+
+	// Check the cache.
+	if value, found := nsc.tsCache.Get(tsKey); found {
+		entry := value.(*typeSystemCacheEntry)
+		return entry.nsDef, entry.ts, nil
+	}
+
+	// We couldn't use the cached entry; build one. This runs inside the same
+	// singleflight critical section as the namespace definition read (via
+	// the nested ReadNamespace call) so concurrent callers for the same
+	// tenant, namespace, and revision share the work of both the read and
+	// the build.
+	loadedRaw, err, _ := nsc.readTsGroup.Do(tsKey, func() (interface{}, error) {
+		nsDef, err := nsc.ReadNamespace(ctx, nsName, revision)
+		if err != nil {
+			return nil, err
+		}
+
+		span.AddEvent("Building namespace type system")
+		recorder := newDependencyRecordingManager(nsc)
+		ts, terr := BuildNamespaceTypeSystemForManager(nsDef, recorder, revision)
+		if terr != nil {
+			return nil, terr
+		}
 
-	// TODO(jschorr): Cache the type system too
-	ts, terr := BuildNamespaceTypeSystemForManager(nsDef, nsc, revision)
-	return nsDef, ts, terr
+		entry := &typeSystemCacheEntry{nsDef: nsDef, ts: ts}
+		nsc.tsCache.Set(tsKey, entry, int64(proto.Size(nsDef)), nsc.expiration)
+		nsc.trackTypeSystemDependency(shard, nsName, tsKey)
+		for _, dep := range recorder.dependencies() {
+			nsc.trackTypeSystemDependency(shard, dep, tsKey)
+		}
+		span.AddEvent("Saved type system to cache")
+
+		return entry, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entry := loadedRaw.(*typeSystemCacheEntry)
+	return entry.nsDef, entry.ts, nil
 }
 
 func (nsc *cachingManager) ReadNamespace(ctx context.Context, nsName string, revision decimal.Decimal) (*v0.NamespaceDefinition, error) {
@@ -66,19 +282,22 @@ func (nsc *cachingManager) ReadNamespace(ctx context.Context, nsName string, rev
 	defer span.End()
 
 	ds := datastoremw.MustFromContext(ctx)
+	tenantID := nsc.tenantID(ctx)
+	shard := nsc.shardFor(tenantID)
 
 	// Check the cache.
 	nsRevisionKey, err := ds.NamespaceCacheKey(nsName, revision)
 	if err != nil {
 		return nil, err
 	}
-	value, found := nsc.c.Get(nsRevisionKey)
+	cacheKey := scopeKeyToTenant(tenantID, nsRevisionKey)
+	value, found := nsc.c.Get(cacheKey)
 	if found {
 		return value.(*v0.NamespaceDefinition), nil
 	}
 
 	// We couldn't use the cached entry, load one
-	loadedRaw, err, _ := nsc.readNsGroup.Do(nsRevisionKey, func() (interface{}, error) {
+	loadedRaw, err, _ := nsc.readNsGroup.Do(cacheKey, func() (interface{}, error) {
 		span.AddEvent("Read namespace from delegate (datastore)")
 		loaded, _, err := ds.ReadNamespace(ctx, nsName, revision)
 		if err != nil {
@@ -88,13 +307,9 @@ func (nsc *cachingManager) ReadNamespace(ctx context.Context, nsName string, rev
 		// Remove user-defined metadata.
 		loaded = namespace.FilterUserDefinedMetadata(loaded)
 
-		cacheKey, err := ds.NamespaceCacheKey(nsName, revision)
-		if err != nil {
-			return nil, err
-		}
-
 		// Save it to the cache
-		nsc.c.Set(cacheKey, loaded, int64(proto.Size(loaded)))
+		nsc.c.Set(cacheKey, loaded, int64(proto.Size(loaded)), nsc.expiration)
+		nsc.trackKey(shard, nsName, cacheKey)
 		span.AddEvent("Saved to cache")
 
 		return loaded, err
@@ -109,6 +324,249 @@ func (nsc *cachingManager) ReadNamespace(ctx context.Context, nsName string, rev
 	return loadedRaw.(*v0.NamespaceDefinition), nil
 }
 
+// InvalidateNamespace evicts every cached entry for nsName - across all
+// tenants and revisions - from this manager's local cache, including any
+// cached type system that was built from nsName or that transitively
+// referenced it, and, if an InvalidationTransport was configured via
+// WithInvalidationTransport, publishes an event so that every other manager
+// sharing the cache does the same. revision is the new revision nsName was
+// just written at (or deleted at); it isn't used to scope the eviction
+// itself - a stale definition at any revision must go - but it rides along
+// on the published event so a subscriber can log or record metrics against
+// the write that triggered it. Install WriteThroughDatastore on the
+// datastore write path to call this (or InvalidateNamespaceForTenant, for a
+// WithTenantScoping manager) after every WriteNamespace or DeleteNamespace,
+// so other SpiceDB nodes stop serving the stale definition immediately
+// instead of waiting for their TTL to elapse.
+func (nsc *cachingManager) InvalidateNamespace(nsName string, revision decimal.Decimal) error {
+	nsc.evictNamespaceAllTenants(nsName)
+
+	if nsc.invalidation == nil {
+		return nil
+	}
+	return nsc.invalidation.Publish(context.Background(), InvalidationEvent{Namespace: nsName, Revision: revision.String()})
+}
+
+// InvalidateNamespaceForTenant behaves like InvalidateNamespace, but scopes
+// the eviction - and the published InvalidationEvent, if any - to a single
+// tenant's shard, leaving every other tenant's cached entries for nsName
+// untouched.
+func (nsc *cachingManager) InvalidateNamespaceForTenant(tenantID, nsName string, revision decimal.Decimal) error {
+	nsc.evictNamespace(nsc.shardFor(tenantID), nsName)
+
+	if nsc.invalidation == nil {
+		return nil
+	}
+	return nsc.invalidation.Publish(context.Background(), InvalidationEvent{Namespace: nsName, TenantID: tenantID, Revision: revision.String()})
+}
+
+// EvictTenant implements TenantAwareManager.
+func (nsc *cachingManager) EvictTenant(tenantID string) error {
+	nsc.shardsMu.Lock()
+	shard, ok := nsc.shards[tenantID]
+	delete(nsc.shards, tenantID)
+	nsc.shardsMu.Unlock()
+	if !ok {
+		return nil
+	}
+	shard.markEvicted()
+
+	shard.mu.Lock()
+	keys := make([]string, 0, len(shard.nsKeys))
+	for _, nsKeys := range shard.nsKeys {
+		keys = append(keys, nsKeys...)
+	}
+	shard.nsKeys = make(map[string][]string)
+	shard.mu.Unlock()
+	for _, key := range keys {
+		nsc.c.Delete(key)
+	}
+
+	shard.tsDepsMu.Lock()
+	tsKeys := make([]string, 0, len(shard.tsDeps))
+	for _, deps := range shard.tsDeps {
+		tsKeys = append(tsKeys, deps...)
+	}
+	shard.tsDeps = make(map[string][]string)
+	shard.tsDepsMu.Unlock()
+	for _, tsKey := range tsKeys {
+		nsc.tsCache.Delete(tsKey)
+	}
+
+	return nil
+}
+
+// TenantMetrics implements TenantAwareManager.
+func (nsc *cachingManager) TenantMetrics(tenantID string) TenantCacheMetrics {
+	nsc.shardsMu.Lock()
+	shard, ok := nsc.shards[tenantID]
+	nsc.shardsMu.Unlock()
+	if !ok {
+		return TenantCacheMetrics{}
+	}
+
+	shard.mu.Lock()
+	cachedNamespaces := len(shard.nsKeys)
+	shard.mu.Unlock()
+
+	shard.tsDepsMu.Lock()
+	cachedTypeSystems := len(shard.tsDeps)
+	shard.tsDepsMu.Unlock()
+
+	return TenantCacheMetrics{CachedNamespaces: cachedNamespaces, CachedTypeSystems: cachedTypeSystems}
+}
+
+// handleInvalidation is the InvalidationTransport subscriber callback; it
+// applies a received event to this manager's local cache.
+func (nsc *cachingManager) handleInvalidation(event InvalidationEvent) {
+	if event.BulkClear {
+		nsc.clearAll()
+		return
+	}
+	if event.TenantID == "" {
+		nsc.evictNamespaceAllTenants(event.Namespace)
+		return
+	}
+	nsc.evictNamespace(nsc.shardFor(event.TenantID), event.Namespace)
+}
+
+// tenantID resolves the current tenant from ctx via the resolver configured
+// with WithTenantScoping, or "" - the shared default tenant - if none was
+// configured.
+func (nsc *cachingManager) tenantID(ctx context.Context) string {
+	if nsc.tenantResolver == nil {
+		return ""
+	}
+	return nsc.tenantResolver(ctx)
+}
+
+// shardFor returns the tenantShard holding cache bookkeeping for tenantID,
+// creating it on first use.
+func (nsc *cachingManager) shardFor(tenantID string) *tenantShard {
+	nsc.shardsMu.Lock()
+	defer nsc.shardsMu.Unlock()
+
+	shard, ok := nsc.shards[tenantID]
+	if !ok {
+		shard = newTenantShard()
+		nsc.shards[tenantID] = shard
+	}
+	return shard
+}
+
+// scopeKeyToTenant prefixes key with tenantID, so that entries for the same
+// underlying cache key never collide across tenants sharing one
+// NamespaceCache backend. The default, unscoped tenant ("") is left
+// unprefixed so enabling WithTenantScoping doesn't invalidate an
+// already-warm cache.
+func scopeKeyToTenant(tenantID, key string) string {
+	if tenantID == "" {
+		return key
+	}
+	return tenantID + "/" + key
+}
+
+// trackKey records that cacheKey (for nsName, at some revision) was written
+// to the cache, so a later eviction of nsName in shard can evict it without
+// needing to know which revisions were ever looked up.
+func (nsc *cachingManager) trackKey(shard *tenantShard, nsName, cacheKey string) {
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	// The shard was evicted (and its map drained) after we were handed it
+	// via shardFor. Tracking the key here would resurrect it into a shard
+	// nothing will evict again, leaking the entry past its eviction.
+	if shard.isEvicted() {
+		return
+	}
+
+	for _, existing := range shard.nsKeys[nsName] {
+		if existing == cacheKey {
+			return
+		}
+	}
+	shard.nsKeys[nsName] = append(shard.nsKeys[nsName], cacheKey)
+}
+
+// evictNamespace evicts every cache entry shard has tracked for nsName,
+// including any dependent type systems.
+func (nsc *cachingManager) evictNamespace(shard *tenantShard, nsName string) {
+	shard.mu.Lock()
+	keys := shard.nsKeys[nsName]
+	delete(shard.nsKeys, nsName)
+	shard.mu.Unlock()
+
+	for _, key := range keys {
+		nsc.c.Delete(key)
+	}
+
+	nsc.evictDependentTypeSystems(shard, nsName)
+}
+
+// evictNamespaceAllTenants evicts every cache entry tracked for nsName,
+// across every tenant shard - the right behavior for invalidation sources
+// (e.g. a schema write) that don't themselves know which tenants are
+// affected.
+func (nsc *cachingManager) evictNamespaceAllTenants(nsName string) {
+	nsc.shardsMu.Lock()
+	shards := make([]*tenantShard, 0, len(nsc.shards))
+	for _, shard := range nsc.shards {
+		shards = append(shards, shard)
+	}
+	nsc.shardsMu.Unlock()
+
+	for _, shard := range shards {
+		nsc.evictNamespace(shard, nsName)
+	}
+}
+
+// trackTypeSystemDependency records that the type-system cache entry stored
+// under tsKey depends on nsName - either because tsKey *is* the type system
+// built for nsName, or because building it read nsName as a referenced
+// namespace. A later eviction of nsName in shard evicts tsKey along with it,
+// so a cached type system never outlives a namespace it was built from.
+func (nsc *cachingManager) trackTypeSystemDependency(shard *tenantShard, nsName, tsKey string) {
+	shard.tsDepsMu.Lock()
+	defer shard.tsDepsMu.Unlock()
+
+	// See the matching check in trackKey: avoid resurrecting an entry into
+	// a shard that EvictTenant has already drained.
+	if shard.isEvicted() {
+		return
+	}
+
+	for _, existing := range shard.tsDeps[nsName] {
+		if existing == tsKey {
+			return
+		}
+	}
+	shard.tsDeps[nsName] = append(shard.tsDeps[nsName], tsKey)
+}
+
+// evictDependentTypeSystems evicts every cached type system shard has
+// tracked as built transitively from nsName, at any revision.
+func (nsc *cachingManager) evictDependentTypeSystems(shard *tenantShard, nsName string) {
+	shard.tsDepsMu.Lock()
+	tsKeys := shard.tsDeps[nsName]
+	delete(shard.tsDeps, nsName)
+	shard.tsDepsMu.Unlock()
+
+	for _, tsKey := range tsKeys {
+		nsc.tsCache.Delete(tsKey)
+	}
+}
+
+func (nsc *cachingManager) clearAll() {
+	nsc.shardsMu.Lock()
+	nsc.shards = make(map[string]*tenantShard)
+	nsc.shardsMu.Unlock()
+
+	// Best-effort: if the clear fails, entries simply age out by TTL as
+	// before.
+	_ = nsc.c.Clear()
+	_ = nsc.tsCache.Clear()
+}
+
 func (nsc *cachingManager) CheckNamespaceAndRelation(ctx context.Context, namespace, relation string, allowEllipsis bool, revision decimal.Decimal) error {
 	config, err := nsc.ReadNamespace(ctx, namespace, revision)
 	if err != nil {
@@ -129,6 +587,11 @@ func (nsc *cachingManager) CheckNamespaceAndRelation(ctx context.Context, namesp
 }
 
 func (nsc *cachingManager) Close() error {
-	nsc.c.Close()
-	return nil
+	if nsc.invalidationUnsub != nil {
+		nsc.invalidationUnsub()
+	}
+	if err := nsc.c.Close(); err != nil {
+		return err
+	}
+	return nsc.tsCache.Close()
 }
@@ -0,0 +1,189 @@
+package namespace
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	v0 "github.com/authzed/authzed-go/proto/authzed/api/v0"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore"
+	datastoremw "github.com/authzed/spicedb/internal/middleware/datastore"
+)
+
+// fakeDatastore is a minimal datastore.Datastore double that only
+// implements the two methods cachingManager actually calls -
+// ReadNamespace and NamespaceCacheKey - plus WriteNamespace and
+// DeleteNamespace for the WriteThroughDatastore tests. It embeds the (nil) interface so it
+// satisfies datastore.Datastore in full; any other method panics if
+// exercised, which none of these tests do.
+type fakeDatastore struct {
+	datastore.Datastore
+
+	mu     sync.Mutex
+	reads  int
+	nsDefs map[string]*v0.NamespaceDefinition
+}
+
+func newFakeDatastore(nsDefs map[string]*v0.NamespaceDefinition) *fakeDatastore {
+	return &fakeDatastore{nsDefs: nsDefs}
+}
+
+func (f *fakeDatastore) ReadNamespace(_ context.Context, nsName string, revision decimal.Decimal) (*v0.NamespaceDefinition, decimal.Decimal, error) {
+	f.mu.Lock()
+	f.reads++
+	f.mu.Unlock()
+
+	nsDef, ok := f.nsDefs[nsName]
+	if !ok {
+		return nil, decimal.Zero, datastore.ErrNamespaceNotFound{}
+	}
+	return nsDef, revision, nil
+}
+
+func (f *fakeDatastore) NamespaceCacheKey(nsName string, revision decimal.Decimal) (string, error) {
+	return nsName + "@" + revision.String(), nil
+}
+
+func (f *fakeDatastore) WriteNamespace(_ context.Context, nsDef *v0.NamespaceDefinition) (decimal.Decimal, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.nsDefs == nil {
+		f.nsDefs = make(map[string]*v0.NamespaceDefinition)
+	}
+	f.nsDefs[nsDef.Name] = nsDef
+	return decimal.NewFromInt(1), nil
+}
+
+func (f *fakeDatastore) DeleteNamespace(_ context.Context, nsName string) (decimal.Decimal, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.nsDefs, nsName)
+	return decimal.NewFromInt(2), nil
+}
+
+func (f *fakeDatastore) readCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.reads
+}
+
+func contextWithFakeDatastore(ds datastore.Datastore) context.Context {
+	ctx, handle := datastoremw.ContextWithHandle(context.Background())
+	handle.SetDatastore(ds)
+	return ctx
+}
+
+func TestReadNamespaceCachesAcrossCalls(t *testing.T) {
+	require := require.New(t)
+
+	ds := newFakeDatastore(map[string]*v0.NamespaceDefinition{
+		"document": {Name: "document"},
+	})
+	ctx := contextWithFakeDatastore(ds)
+	revision := decimal.NewFromInt(1)
+
+	// fakeCache makes a Set visible to the very next Get; ristretto applies
+	// new entries asynchronously, so the immediate second read below could
+	// otherwise miss.
+	manager, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(newFakeCache()))
+	require.NoError(err)
+	defer manager.Close()
+
+	nsDef, err := manager.ReadNamespace(ctx, "document", revision)
+	require.NoError(err)
+	require.Equal("document", nsDef.Name)
+	require.Equal(1, ds.readCount())
+
+	// A second call for the same namespace and revision must be served
+	// from the cache rather than reading the datastore again.
+	nsDef, err = manager.ReadNamespace(ctx, "document", revision)
+	require.NoError(err)
+	require.Equal("document", nsDef.Name)
+	require.Equal(1, ds.readCount())
+}
+
+func TestReadNamespaceMissPropagatesNotFound(t *testing.T) {
+	require := require.New(t)
+
+	ds := newFakeDatastore(map[string]*v0.NamespaceDefinition{})
+	ctx := contextWithFakeDatastore(ds)
+
+	manager, err := NewCachingNamespaceManager(0, nil)
+	require.NoError(err)
+	defer manager.Close()
+
+	_, err = manager.ReadNamespace(ctx, "missing", decimal.NewFromInt(1))
+	require.Error(err)
+}
+
+func TestReadNamespaceFallsBackToDatastoreWhenCacheUnreachable(t *testing.T) {
+	require := require.New(t)
+
+	ds := newFakeDatastore(map[string]*v0.NamespaceDefinition{
+		"document": {Name: "document"},
+	})
+	ctx := contextWithFakeDatastore(ds)
+	revision := decimal.NewFromInt(1)
+
+	// unreachableCache simulates a shared Redis cache that's down: every
+	// Get misses, so ReadNamespace must fall back to the datastore on
+	// every single call instead of erroring or serving stale data.
+	cache := &unreachableCache{fakeCache: *newFakeCache()}
+	manager, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cache))
+	require.NoError(err)
+	defer manager.Close()
+
+	for i := 1; i <= 3; i++ {
+		nsDef, err := manager.ReadNamespace(ctx, "document", revision)
+		require.NoError(err)
+		require.Equal("document", nsDef.Name)
+		require.Equal(i, ds.readCount(), "an unreachable cache must fall back to the datastore on every call")
+	}
+}
+
+// BenchmarkReadNamespaceAndTypes demonstrates the win the type-system cache
+// is meant to deliver on check-heavy workloads: once a namespace's type
+// system has been built once, a steady stream of ReadNamespaceAndTypes
+// calls for that same (namespace, revision) pair - the access pattern of a
+// Check-heavy service - should hit nsc.tsCache instead of re-walking every
+// relation and resolving every referenced namespace on each call.
+func BenchmarkReadNamespaceAndTypes(b *testing.B) {
+	ds := newFakeDatastore(map[string]*v0.NamespaceDefinition{
+		"document": {Name: "document"},
+	})
+	ctx := contextWithFakeDatastore(ds)
+	revision := decimal.NewFromInt(1)
+
+	// Synchronous fakeCache backends, so the first loop iterations can't
+	// miss an entry that ristretto hasn't applied yet.
+	manager, err := NewCachingNamespaceManager(0, nil,
+		WithCacheBackend(newFakeCache()), WithTypeSystemCacheBackend(newFakeCache()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer manager.Close()
+
+	// Warm the cache once so the loop below measures steady-state
+	// check-heavy traffic, not the one-time build cost.
+	if _, _, err := manager.ReadNamespaceAndTypes(ctx, "document", revision); err != nil {
+		b.Fatal(err)
+	}
+	if reads := ds.readCount(); reads != 1 {
+		b.Fatalf("expected exactly one datastore read while warming the cache, got %d", reads)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := manager.ReadNamespaceAndTypes(ctx, "document", revision); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	if reads := ds.readCount(); reads != 1 {
+		b.Fatalf("expected the datastore to be read only once across %d cached lookups, got %d reads", b.N, reads)
+	}
+}
@@ -0,0 +1,197 @@
+package namespace
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCache is a minimal in-memory NamespaceCache used to exercise
+// cachingManager without depending on a real ristretto or Redis deployment.
+type fakeCache struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+	closed bool
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{values: make(map[string]interface{})}
+}
+
+func (f *fakeCache) Get(key string) (interface{}, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.values[key]
+	return v, ok
+}
+
+func (f *fakeCache) Set(key string, value interface{}, _ int64, _ time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[key] = value
+}
+
+func (f *fakeCache) Delete(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.values, key)
+}
+
+func (f *fakeCache) Clear() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values = make(map[string]interface{})
+	return nil
+}
+
+func (f *fakeCache) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+// unreachableCache simulates a Redis backend that cannot be reached: every
+// Get misses and every Set is silently dropped, mirroring how redisCache
+// behaves when the connection is down.
+type unreachableCache struct {
+	fakeCache
+}
+
+func (u *unreachableCache) Get(string) (interface{}, bool) {
+	return nil, false
+}
+
+func (u *unreachableCache) Set(string, interface{}, int64, time.Duration) {}
+
+func TestWithCacheBackendSelectsProvidedCache(t *testing.T) {
+	require := require.New(t)
+
+	cache := newFakeCache()
+	manager, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cache))
+	require.NoError(err)
+
+	cm, ok := manager.(*cachingManager)
+	require.True(ok)
+	require.Same(NamespaceCache(cache), cm.c)
+}
+
+func TestCachingManagerDefaultsToRistretto(t *testing.T) {
+	require := require.New(t)
+
+	manager, err := NewCachingNamespaceManager(0, nil)
+	require.NoError(err)
+	defer manager.Close()
+
+	cm, ok := manager.(*cachingManager)
+	require.True(ok)
+	_, isRistretto := cm.c.(*ristrettoCache)
+	require.True(isRistretto)
+}
+
+func TestFakeCacheHitAndMiss(t *testing.T) {
+	require := require.New(t)
+
+	cache := newFakeCache()
+
+	_, found := cache.Get("missing")
+	require.False(found)
+
+	cache.Set("present", "value", 1, 0)
+	value, found := cache.Get("present")
+	require.True(found)
+	require.Equal("value", value)
+}
+
+func TestUnreachableBackendAlwaysMisses(t *testing.T) {
+	require := require.New(t)
+
+	cache := &unreachableCache{fakeCache: *newFakeCache()}
+	cache.Set("key", "value", 1, 0)
+
+	// A caller relying solely on the cache would fall back to the
+	// datastore here, which is exactly the behavior we want when the
+	// shared Redis cache is unreachable.
+	_, found := cache.Get("key")
+	require.False(found)
+}
+
+func TestWithTypeSystemCacheBackendSelectsProvidedCache(t *testing.T) {
+	require := require.New(t)
+
+	cache := newFakeCache()
+	manager, err := NewCachingNamespaceManager(0, nil, WithTypeSystemCacheBackend(cache))
+	require.NoError(err)
+
+	cm, ok := manager.(*cachingManager)
+	require.True(ok)
+	require.Same(NamespaceCache(cache), cm.tsCache)
+}
+
+func TestEvictNamespaceEvictsDependentTypeSystems(t *testing.T) {
+	require := require.New(t)
+
+	tsCache := newFakeCache()
+	manager, err := NewCachingNamespaceManager(0, nil, WithTypeSystemCacheBackend(tsCache))
+	require.NoError(err)
+	defer manager.Close()
+
+	cm, ok := manager.(*cachingManager)
+	require.True(ok)
+	shard := cm.shardFor("")
+
+	// "document" directly owns tsKey, and "user" is merely a referenced
+	// namespace that the type system built for tsKey transitively depended
+	// on - evicting either must drop the cached entry.
+	const tsKey = "typesystem/document@rev1"
+	tsCache.Set(tsKey, &typeSystemCacheEntry{}, 0, 0)
+	cm.trackTypeSystemDependency(shard, "document", tsKey)
+	cm.trackTypeSystemDependency(shard, "user", tsKey)
+
+	cm.evictNamespace(shard, "user")
+	_, found := tsCache.Get(tsKey)
+	require.False(found, "evicting a transitive dependency must evict the dependent type system")
+}
+
+func TestEvictTenantLeavesOtherTenantsUntouched(t *testing.T) {
+	require := require.New(t)
+
+	cache := newFakeCache()
+	manager, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cache),
+		WithTenantScoping(func(ctx context.Context) string {
+			id, _ := ctx.Value(tenantCtxKey{}).(string)
+			return id
+		}))
+	require.NoError(err)
+	defer manager.Close()
+
+	cm, ok := manager.(*cachingManager)
+	require.True(ok)
+
+	tenantA := cm.shardFor("tenant-a")
+	tenantB := cm.shardFor("tenant-b")
+	cache.Set("tenant-a/document@rev1", "a-value", 0, 0)
+	cache.Set("tenant-b/document@rev1", "b-value", 0, 0)
+	cm.trackKey(tenantA, "document", "tenant-a/document@rev1")
+	cm.trackKey(tenantB, "document", "tenant-b/document@rev1")
+
+	var evictor TenantAwareManager = cm
+	require.NoError(evictor.EvictTenant("tenant-a"))
+
+	_, found := cache.Get("tenant-a/document@rev1")
+	require.False(found, "evicting tenant-a must drop its cached entries")
+
+	_, found = cache.Get("tenant-b/document@rev1")
+	require.True(found, "evicting tenant-a must not touch tenant-b's cached entries")
+
+	metrics := evictor.TenantMetrics("tenant-b")
+	require.Equal(1, metrics.CachedNamespaces)
+}
+
+// tenantCtxKey is a stand-in for a production tenant-resolution scheme
+// (e.g. the tenant package's own context key) used only to exercise
+// WithTenantScoping in tests.
+type tenantCtxKey struct{}
@@ -0,0 +1,167 @@
+package namespace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// InvalidationEvent describes a namespace whose cached definition (and any
+// type systems derived from it, see NamespaceTypeSystem caching) is no
+// longer valid and must be evicted by every manager sharing a cache.
+type InvalidationEvent struct {
+	// Namespace is the name of the namespace to evict. Ignored when
+	// BulkClear is set.
+	Namespace string `json:"namespace"`
+
+	// Revision is the new revision Namespace was written (or deleted) at
+	// that triggered this event. Eviction itself is namespace-wide
+	// regardless of revision, so Revision plays no part in deciding what
+	// gets evicted - it's carried purely so a subscriber can log or record
+	// metrics against the write that caused the invalidation. Left empty
+	// for a BulkClear event, which isn't tied to any single write.
+	Revision string `json:"revision,omitempty"`
+
+	// TenantID scopes the eviction to a single tenant's shard of a manager
+	// configured with WithTenantScoping, leaving every other tenant's
+	// cached entries for Namespace untouched. Left empty, the event is
+	// treated as tenant-agnostic and evicts Namespace from every tenant -
+	// the right default for publishers (e.g. a schema write path) that
+	// don't themselves know which tenants are affected.
+	TenantID string `json:"tenantId,omitempty"`
+
+	// BulkClear indicates that every manager receiving the event should drop
+	// its entire cache, e.g. following a schema migration that touches many
+	// namespaces at once.
+	BulkClear bool `json:"bulkClear"`
+}
+
+// InvalidationTransport delivers InvalidationEvents published by one
+// manager (typically the one handling a WriteNamespace or DeleteNamespace)
+// to every manager sharing the same cache, including itself.
+type InvalidationTransport interface {
+	// Publish broadcasts event to all current subscribers.
+	Publish(ctx context.Context, event InvalidationEvent) error
+
+	// Subscribe registers handler to be called for every event published
+	// after it is called. The returned function removes the subscription.
+	Subscribe(handler func(InvalidationEvent)) (unsubscribe func(), err error)
+
+	// Close releases any resources held by the transport.
+	Close() error
+}
+
+// inProcessTransport is an InvalidationTransport that only delivers events
+// within the current process. It's useful for tests, and for deployments
+// running multiple managers in one process that still want them kept in
+// sync without standing up Redis.
+type inProcessTransport struct {
+	mu       sync.Mutex
+	handlers map[int]func(InvalidationEvent)
+	nextID   int
+}
+
+// NewInProcessInvalidationTransport creates an InvalidationTransport that
+// delivers events to subscribers within the current process only.
+func NewInProcessInvalidationTransport() InvalidationTransport {
+	return &inProcessTransport{handlers: make(map[int]func(InvalidationEvent))}
+}
+
+func (t *inProcessTransport) Publish(_ context.Context, event InvalidationEvent) error {
+	t.mu.Lock()
+	handlers := make([]func(InvalidationEvent), 0, len(t.handlers))
+	for _, handler := range t.handlers {
+		handlers = append(handlers, handler)
+	}
+	t.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+	return nil
+}
+
+func (t *inProcessTransport) Subscribe(handler func(InvalidationEvent)) (func(), error) {
+	t.mu.Lock()
+	id := t.nextID
+	t.nextID++
+	t.handlers[id] = handler
+	t.mu.Unlock()
+
+	return func() {
+		t.mu.Lock()
+		delete(t.handlers, id)
+		t.mu.Unlock()
+	}, nil
+}
+
+func (t *inProcessTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers = nil
+	return nil
+}
+
+// redisInvalidationTransport is an InvalidationTransport backed by Redis
+// Pub/Sub, used to propagate invalidations to every SpiceDB instance
+// sharing a Redis-backed NamespaceCache.
+type redisInvalidationTransport struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisInvalidationTransport creates an InvalidationTransport that
+// publishes and receives InvalidationEvents over the given Redis Pub/Sub
+// channel. The caller retains ownership of client and is responsible for
+// closing it.
+func NewRedisInvalidationTransport(client *redis.Client, channel string) InvalidationTransport {
+	return &redisInvalidationTransport{client: client, channel: channel}
+}
+
+func (t *redisInvalidationTransport) Publish(ctx context.Context, event InvalidationEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to marshal invalidation event: %w", err)
+	}
+	return t.client.Publish(ctx, t.channel, raw).Err()
+}
+
+func (t *redisInvalidationTransport) Subscribe(handler func(InvalidationEvent)) (func(), error) {
+	sub := t.client.Subscribe(context.Background(), t.channel)
+	if _, err := sub.Receive(context.Background()); err != nil {
+		_ = sub.Close()
+		return nil, fmt.Errorf("unable to subscribe to invalidation channel: %w", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ch := sub.Channel()
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var event InvalidationEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					continue
+				}
+				handler(event)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		_ = sub.Close()
+	}, nil
+}
+
+func (t *redisInvalidationTransport) Close() error {
+	return nil
+}
@@ -0,0 +1,118 @@
+package namespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInProcessTransportDeliversToAllSubscribers(t *testing.T) {
+	require := require.New(t)
+
+	transport := NewInProcessInvalidationTransport()
+
+	var receivedA, receivedB []InvalidationEvent
+	unsubA, err := transport.Subscribe(func(e InvalidationEvent) { receivedA = append(receivedA, e) })
+	require.NoError(err)
+	defer unsubA()
+
+	unsubB, err := transport.Subscribe(func(e InvalidationEvent) { receivedB = append(receivedB, e) })
+	require.NoError(err)
+	defer unsubB()
+
+	require.NoError(transport.Publish(context.Background(), InvalidationEvent{Namespace: "document"}))
+
+	require.Equal([]InvalidationEvent{{Namespace: "document"}}, receivedA)
+	require.Equal([]InvalidationEvent{{Namespace: "document"}}, receivedB)
+}
+
+func TestInProcessTransportUnsubscribeStopsDelivery(t *testing.T) {
+	require := require.New(t)
+
+	transport := NewInProcessInvalidationTransport()
+
+	var received []InvalidationEvent
+	unsub, err := transport.Subscribe(func(e InvalidationEvent) { received = append(received, e) })
+	require.NoError(err)
+
+	unsub()
+	require.NoError(transport.Publish(context.Background(), InvalidationEvent{Namespace: "document"}))
+	require.Empty(received)
+}
+
+func TestCachingManagerInvalidateNamespaceEvictsAllRevisions(t *testing.T) {
+	require := require.New(t)
+
+	cache := newFakeCache()
+	manager, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cache))
+	require.NoError(err)
+
+	cm, ok := manager.(*cachingManager)
+	require.True(ok)
+
+	shard := cm.shardFor("")
+	cm.trackKey(shard, "document", "document@rev1")
+	cm.trackKey(shard, "document", "document@rev2")
+	cache.Set("document@rev1", "v1", 1, 0)
+	cache.Set("document@rev2", "v2", 1, 0)
+
+	require.NoError(cm.InvalidateNamespace("document", decimal.NewFromInt(2)))
+
+	_, found := cache.Get("document@rev1")
+	require.False(found)
+	_, found = cache.Get("document@rev2")
+	require.False(found)
+}
+
+func TestCachingManagerBulkClearEvictsEverything(t *testing.T) {
+	require := require.New(t)
+
+	cache := newFakeCache()
+	manager, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cache))
+	require.NoError(err)
+
+	cm, ok := manager.(*cachingManager)
+	require.True(ok)
+
+	cm.trackKey(cm.shardFor(""), "document", "document@rev1")
+	cache.Set("document@rev1", "v1", 1, 0)
+
+	cm.handleInvalidation(InvalidationEvent{BulkClear: true})
+
+	_, found := cache.Get("document@rev1")
+	require.False(found)
+}
+
+func TestCachingManagerPropagatesInvalidationAcrossManagers(t *testing.T) {
+	require := require.New(t)
+
+	transport := NewInProcessInvalidationTransport()
+
+	cacheA := newFakeCache()
+	managerA, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cacheA), WithInvalidationTransport(transport))
+	require.NoError(err)
+	defer managerA.Close()
+
+	cacheB := newFakeCache()
+	managerB, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cacheB), WithInvalidationTransport(transport))
+	require.NoError(err)
+	defer managerB.Close()
+
+	cmA := managerA.(*cachingManager)
+	cmB := managerB.(*cachingManager)
+
+	cmA.trackKey(cmA.shardFor(""), "document", "document@rev1")
+	cacheA.Set("document@rev1", "v1", 1, 0)
+
+	cmB.trackKey(cmB.shardFor(""), "document", "document@rev1")
+	cacheB.Set("document@rev1", "v1", 1, 0)
+
+	require.NoError(cmA.InvalidateNamespace("document", decimal.NewFromInt(2)))
+
+	_, found := cacheA.Get("document@rev1")
+	require.False(found)
+	_, found = cacheB.Get("document@rev1")
+	require.False(found)
+}
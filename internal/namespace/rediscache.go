@@ -0,0 +1,123 @@
+package namespace
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	v0 "github.com/authzed/authzed-go/proto/authzed/api/v0"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+// RedisCacheConfig configures a Redis-backed NamespaceCache, allowing a
+// shared namespace cache to be run across a fleet of SpiceDB instances
+// instead of each process keeping its own in-memory copy.
+type RedisCacheConfig struct {
+	// Addr is the host:port of the Redis server (or a single node of a
+	// cluster/sentinel deployment).
+	Addr string
+
+	// Username and Password are used for AUTH, if the Redis deployment
+	// requires it.
+	Username string
+	Password string
+
+	// DB selects the Redis logical database to use.
+	DB int
+
+	// PoolSize caps the number of connections kept open to Redis. A zero
+	// value defers to the go-redis default.
+	PoolSize int
+
+	// TLSConfig, if non-nil, causes the client to connect to Redis over TLS.
+	TLSConfig *tls.Config
+
+	// DialTimeout, ReadTimeout and WriteTimeout bound the corresponding Redis
+	// operations. Zero values defer to the go-redis defaults.
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// redisCache is a NamespaceCache backed by a Redis instance shared across
+// SpiceDB processes. Values are stored as marshalled v0.NamespaceDefinition
+// proto messages.
+type redisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache creates a NamespaceCache backed by Redis, for use with
+// WithCacheBackend. Its values are marshalled *v0.NamespaceDefinition proto
+// messages, so it cannot also back WithTypeSystemCacheBackend, which stores
+// a *NamespaceTypeSystem - a different, non-proto payload. Passing one to
+// WithTypeSystemCacheBackend is rejected at configuration time rather than
+// silently missing on every lookup.
+func NewRedisCache(config RedisCacheConfig) (NamespaceCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:         config.Addr,
+		Username:     config.Username,
+		Password:     config.Password,
+		DB:           config.DB,
+		PoolSize:     config.PoolSize,
+		TLSConfig:    config.TLSConfig,
+		DialTimeout:  config.DialTimeout,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("unable to connect to redis namespace cache: %w", err)
+	}
+
+	return &redisCache{client: client}, nil
+}
+
+func (rc *redisCache) Get(key string) (interface{}, bool) {
+	raw, err := rc.client.Get(context.Background(), key).Bytes()
+	if err != nil {
+		// Covers redis.Nil (key not found) as well as any transient
+		// connectivity error; either way the caller falls back to the
+		// datastore.
+		return nil, false
+	}
+
+	nsDef := &v0.NamespaceDefinition{}
+	if err := proto.Unmarshal(raw, nsDef); err != nil {
+		return nil, false
+	}
+
+	return nsDef, true
+}
+
+func (rc *redisCache) Set(key string, value interface{}, _ int64, ttl time.Duration) {
+	nsDef, ok := value.(*v0.NamespaceDefinition)
+	if !ok {
+		return
+	}
+
+	raw, err := proto.Marshal(nsDef)
+	if err != nil {
+		return
+	}
+
+	// Best-effort: a failed write just means the next reader misses the
+	// cache and falls back to the datastore.
+	rc.client.Set(context.Background(), key, raw, ttl)
+}
+
+func (rc *redisCache) Delete(key string) {
+	rc.client.Del(context.Background(), key)
+}
+
+// Clear drops every key in the configured Redis logical database. Operators
+// running a shared namespace cache should dedicate a DB to it (via
+// RedisCacheConfig.DB) so this doesn't affect unrelated keyspaces.
+func (rc *redisCache) Clear() error {
+	return rc.client.FlushDB(context.Background()).Err()
+}
+
+func (rc *redisCache) Close() error {
+	return rc.client.Close()
+}
@@ -0,0 +1,107 @@
+package namespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	v0 "github.com/authzed/authzed-go/proto/authzed/api/v0"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestRedisCache(t *testing.T) *redisCache {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return &redisCache{client: client}
+}
+
+func TestRedisCacheSetGetRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	cache := newTestRedisCache(t)
+	nsDef := &v0.NamespaceDefinition{Name: "document"}
+
+	cache.Set("document@rev1", nsDef, 0, time.Minute)
+
+	value, found := cache.Get("document@rev1")
+	require.True(found)
+	require.True(proto.Equal(nsDef, value.(*v0.NamespaceDefinition)))
+}
+
+func TestRedisCacheGetMissesOnUnknownKey(t *testing.T) {
+	require := require.New(t)
+
+	cache := newTestRedisCache(t)
+
+	_, found := cache.Get("missing")
+	require.False(found)
+}
+
+func TestRedisCacheGetTreatsCorruptPayloadAsMiss(t *testing.T) {
+	require := require.New(t)
+
+	cache := newTestRedisCache(t)
+	require.NoError(cache.client.Set(context.Background(), "document@rev1", "not a valid proto message", 0).Err())
+
+	_, found := cache.Get("document@rev1")
+	require.False(found, "a payload that doesn't unmarshal as a NamespaceDefinition must be treated as a miss, not panic")
+}
+
+func TestRedisCacheSetIgnoresNonNamespaceDefinitionValues(t *testing.T) {
+	require := require.New(t)
+
+	cache := newTestRedisCache(t)
+	cache.Set("document@rev1", "not a namespace definition", 0, time.Minute)
+
+	_, found := cache.Get("document@rev1")
+	require.False(found, "Set must silently drop a value that isn't a *v0.NamespaceDefinition rather than storing garbage")
+}
+
+func TestRedisCacheSetHonorsTTL(t *testing.T) {
+	require := require.New(t)
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	cache := &redisCache{client: client}
+
+	cache.Set("document@rev1", &v0.NamespaceDefinition{Name: "document"}, 0, time.Minute)
+
+	ttl := server.TTL("document@rev1")
+	require.Greater(ttl, time.Duration(0))
+	require.LessOrEqual(ttl, time.Minute)
+}
+
+func TestRedisCacheDelete(t *testing.T) {
+	require := require.New(t)
+
+	cache := newTestRedisCache(t)
+	cache.Set("document@rev1", &v0.NamespaceDefinition{Name: "document"}, 0, time.Minute)
+
+	cache.Delete("document@rev1")
+
+	_, found := cache.Get("document@rev1")
+	require.False(found)
+}
+
+func TestRedisCacheClearDropsEverything(t *testing.T) {
+	require := require.New(t)
+
+	cache := newTestRedisCache(t)
+	cache.Set("document@rev1", &v0.NamespaceDefinition{Name: "document"}, 0, time.Minute)
+	cache.Set("folder@rev1", &v0.NamespaceDefinition{Name: "folder"}, 0, time.Minute)
+
+	require.NoError(cache.Clear())
+
+	_, found := cache.Get("document@rev1")
+	require.False(found)
+	_, found = cache.Get("folder@rev1")
+	require.False(found)
+}
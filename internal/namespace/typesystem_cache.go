@@ -0,0 +1,74 @@
+package namespace
+
+import (
+	"context"
+	"sync"
+
+	v0 "github.com/authzed/authzed-go/proto/authzed/api/v0"
+	"github.com/shopspring/decimal"
+)
+
+// typeSystemCacheEntry is what's actually stored in a cachingManager's
+// type-system cache: the namespace definition the type system was built
+// from, alongside the type system itself, so ReadNamespaceAndTypes can
+// return both from a single cache hit.
+type typeSystemCacheEntry struct {
+	nsDef *v0.NamespaceDefinition
+	ts    *NamespaceTypeSystem
+}
+
+// typeSystemCacheKey returns the key under which the NamespaceTypeSystem
+// built from the namespace definition at nsRevisionKey is cached. It's
+// distinguished from nsRevisionKey itself so the two caches (definitions
+// and type systems) can share a single NamespaceCache implementation
+// without colliding.
+func typeSystemCacheKey(nsRevisionKey string) string {
+	return "typesystem/" + nsRevisionKey
+}
+
+// dependencyRecordingManager wraps a Manager, recording the name of every
+// namespace read through it. BuildNamespaceTypeSystemForManager resolves
+// referenced namespaces by reading them through the Manager it's given, so
+// running it against a dependencyRecordingManager lets the caller learn,
+// after the fact, exactly which namespaces a built NamespaceTypeSystem
+// transitively depends on - without needing NamespaceTypeSystem itself to
+// expose that.
+type dependencyRecordingManager struct {
+	Manager
+
+	mu   sync.Mutex
+	deps map[string]struct{}
+}
+
+func newDependencyRecordingManager(delegate Manager) *dependencyRecordingManager {
+	return &dependencyRecordingManager{Manager: delegate, deps: make(map[string]struct{})}
+}
+
+func (d *dependencyRecordingManager) ReadNamespace(ctx context.Context, nsName string, revision decimal.Decimal) (*v0.NamespaceDefinition, error) {
+	d.record(nsName)
+	return d.Manager.ReadNamespace(ctx, nsName, revision)
+}
+
+func (d *dependencyRecordingManager) ReadNamespaceAndTypes(ctx context.Context, nsName string, revision decimal.Decimal) (*v0.NamespaceDefinition, *NamespaceTypeSystem, error) {
+	d.record(nsName)
+	return d.Manager.ReadNamespaceAndTypes(ctx, nsName, revision)
+}
+
+func (d *dependencyRecordingManager) record(nsName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deps[nsName] = struct{}{}
+}
+
+// dependencies returns the distinct namespace names read through this
+// manager so far.
+func (d *dependencyRecordingManager) dependencies() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	names := make([]string, 0, len(d.deps))
+	for name := range d.deps {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package namespace
+
+import (
+	"context"
+
+	v0 "github.com/authzed/authzed-go/proto/authzed/api/v0"
+	"github.com/shopspring/decimal"
+
+	"github.com/authzed/spicedb/internal/datastore"
+)
+
+// InvalidatingManager is implemented by every Manager returned by
+// NewCachingNamespaceManager. It's split out from Manager itself because
+// not every Manager need support invalidation (e.g. a test double), so a
+// caller holding only a Manager must type-assert to this - the same
+// pattern TenantAwareManager uses - before calling InvalidateNamespace.
+type InvalidatingManager interface {
+	Manager
+
+	// InvalidateNamespace evicts nsName from this manager's cache; see its
+	// doc comment on cachingManager for the full contract.
+	InvalidateNamespace(nsName string, revision decimal.Decimal) error
+}
+
+// WriteThroughDatastore decorates a datastore.Datastore so that a
+// WriteNamespace or DeleteNamespace it performs also invalidates mgr's
+// cache for the affected namespace, immediately after the underlying write
+// succeeds. Install it via datastoremw.ContextWithHandle on the schema
+// write path so that every SpiceDB instance sharing mgr's cache - directly,
+// or across a fleet via WithInvalidationTransport - stops serving the
+// stale definition right away instead of waiting out its TTL.
+//
+// If mgr was constructed with WithTenantScoping, pass the same tenantID
+// resolver so the invalidation is scoped to the tenant that performed the
+// write rather than evicting nsName for every tenant. A write whose resolver
+// returns "" (the default tenant) still evicts nsName for every tenant.
+type WriteThroughDatastore struct {
+	datastore.Datastore
+
+	mgr      InvalidatingManager
+	tenantID func(ctx context.Context) string
+}
+
+// WrapWithInvalidation returns a WriteThroughDatastore decorating ds. See
+// the WriteThroughDatastore doc comment for how tenantID is used; pass nil
+// if mgr was not constructed with WithTenantScoping.
+func WrapWithInvalidation(ds datastore.Datastore, mgr InvalidatingManager, tenantID func(ctx context.Context) string) *WriteThroughDatastore {
+	return &WriteThroughDatastore{Datastore: ds, mgr: mgr, tenantID: tenantID}
+}
+
+func (d *WriteThroughDatastore) WriteNamespace(ctx context.Context, nsDef *v0.NamespaceDefinition) (decimal.Decimal, error) {
+	revision, err := d.Datastore.WriteNamespace(ctx, nsDef)
+	if err != nil {
+		return revision, err
+	}
+	return revision, d.invalidate(ctx, nsDef.Name, revision)
+}
+
+func (d *WriteThroughDatastore) DeleteNamespace(ctx context.Context, nsName string) (decimal.Decimal, error) {
+	revision, err := d.Datastore.DeleteNamespace(ctx, nsName)
+	if err != nil {
+		return revision, err
+	}
+	return revision, d.invalidate(ctx, nsName, revision)
+}
+
+func (d *WriteThroughDatastore) invalidate(ctx context.Context, nsName string, revision decimal.Decimal) error {
+	if d.tenantID != nil {
+		if tam, ok := d.mgr.(TenantAwareManager); ok {
+			if tenantID := d.tenantID(ctx); tenantID != "" {
+				return tam.InvalidateNamespaceForTenant(tenantID, nsName, revision)
+			}
+		}
+	}
+	return d.mgr.InvalidateNamespace(nsName, revision)
+}
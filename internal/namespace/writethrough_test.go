@@ -0,0 +1,87 @@
+package namespace
+
+import (
+	"context"
+	"testing"
+
+	v0 "github.com/authzed/authzed-go/proto/authzed/api/v0"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteThroughDatastoreInvalidatesOnWriteNamespace(t *testing.T) {
+	require := require.New(t)
+
+	cache := newFakeCache()
+	manager, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cache))
+	require.NoError(err)
+	defer manager.Close()
+
+	cm := manager.(*cachingManager)
+	shard := cm.shardFor("")
+	cm.trackKey(shard, "document", "document@1")
+	cache.Set("document@1", "stale", 1, 0)
+
+	ds := WrapWithInvalidation(newFakeDatastore(nil), cm, nil)
+	_, err = ds.WriteNamespace(context.Background(), &v0.NamespaceDefinition{Name: "document"})
+	require.NoError(err)
+
+	_, found := cache.Get("document@1")
+	require.False(found, "a WriteNamespace through WriteThroughDatastore must invalidate the namespace's prior cache entries")
+}
+
+func TestWriteThroughDatastoreInvalidatesOnDeleteNamespace(t *testing.T) {
+	require := require.New(t)
+
+	cache := newFakeCache()
+	manager, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cache))
+	require.NoError(err)
+	defer manager.Close()
+
+	cm := manager.(*cachingManager)
+	shard := cm.shardFor("")
+	cm.trackKey(shard, "document", "document@1")
+	cache.Set("document@1", "stale", 1, 0)
+
+	ds := WrapWithInvalidation(newFakeDatastore(map[string]*v0.NamespaceDefinition{
+		"document": {Name: "document"},
+	}), cm, nil)
+	_, err = ds.DeleteNamespace(context.Background(), "document")
+	require.NoError(err)
+
+	_, found := cache.Get("document@1")
+	require.False(found, "a DeleteNamespace through WriteThroughDatastore must invalidate the namespace's prior cache entries")
+}
+
+func TestWriteThroughDatastoreScopesInvalidationToTenant(t *testing.T) {
+	require := require.New(t)
+
+	tenantFromCtx := func(ctx context.Context) string {
+		id, _ := ctx.Value(tenantCtxKey{}).(string)
+		return id
+	}
+
+	cache := newFakeCache()
+	manager, err := NewCachingNamespaceManager(0, nil, WithCacheBackend(cache), WithTenantScoping(tenantFromCtx))
+	require.NoError(err)
+	defer manager.Close()
+
+	cm := manager.(*cachingManager)
+	tenantA := cm.shardFor("tenant-a")
+	tenantB := cm.shardFor("tenant-b")
+	cache.Set("tenant-a/document@1", "a-value", 0, 0)
+	cache.Set("tenant-b/document@1", "b-value", 0, 0)
+	cm.trackKey(tenantA, "document", "tenant-a/document@1")
+	cm.trackKey(tenantB, "document", "tenant-b/document@1")
+
+	ds := WrapWithInvalidation(newFakeDatastore(nil), cm, tenantFromCtx)
+
+	ctx := context.WithValue(context.Background(), tenantCtxKey{}, "tenant-a")
+	_, err = ds.WriteNamespace(ctx, &v0.NamespaceDefinition{Name: "document"})
+	require.NoError(err)
+
+	_, found := cache.Get("tenant-a/document@1")
+	require.False(found, "writing as tenant-a must invalidate tenant-a's cached entries")
+
+	_, found = cache.Get("tenant-b/document@1")
+	require.True(found, "writing as tenant-a must not touch tenant-b's cached entries")
+}